@@ -0,0 +1,335 @@
+package tailtracer
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// scopeKey identifies one ScopeSpans within a batch: the resource it belongs
+// to, joined with its own scope id.
+type scopeKey struct {
+	resourceID string
+	scopeID    string
+}
+
+// joinRecordsIntoTraces stitches the decoded resource/scope/span/span_attrs/
+// span_events/span_links record batches back into ptrace.Traces. Spans are
+// attached to their resource/scope via the resource_id/scope_id columns on
+// the span record, and parent-child relationships are carried over verbatim
+// from the span record's parent_span_id column.
+func joinRecordsIntoTraces(records map[string]arrow.Record) (ptrace.Traces, error) {
+	spanRecord, ok := records["span"]
+	if !ok {
+		return ptrace.Traces{}, fmt.Errorf("batch is missing required %q record", "span")
+	}
+
+	traces := ptrace.NewTraces()
+	resources, err := decodeResources(traces, records["resource"])
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+	scopes := decodeScopes(resources, records["scope"])
+
+	spans, err := decodeSpans(scopes, spanRecord)
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+
+	if err := decodeSpanAttributes(spans, records["span_attrs"]); err != nil {
+		return ptrace.Traces{}, err
+	}
+	if err := decodeSpanEvents(spans, records["span_events"]); err != nil {
+		return ptrace.Traces{}, err
+	}
+	if err := decodeSpanLinks(spans, records["span_links"]); err != nil {
+		return ptrace.Traces{}, err
+	}
+
+	return traces, nil
+}
+
+// decodeResources builds one ResourceSpans per row of the resource record,
+// keyed by its resource_id column, so later records can attach to it. If the
+// batch didn't include a resource record, everything falls back to a single
+// anonymous resource.
+func decodeResources(traces ptrace.Traces, rec arrow.Record) (map[string]ptrace.ResourceSpans, error) {
+	byID := make(map[string]ptrace.ResourceSpans)
+
+	if rec == nil {
+		byID[""] = traces.ResourceSpans().AppendEmpty()
+		return byID, nil
+	}
+
+	idCol, hasID := stringColumn(rec, "resource_id")
+	if !hasID && rec.NumRows() > 1 {
+		// Every row would otherwise fall back to the same "" key, silently
+		// collapsing distinct resources onto one another for scope/span
+		// joins further down.
+		return nil, fmt.Errorf("resource record has %d rows but is missing a resource_id column", rec.NumRows())
+	}
+	nameCol, hasName := stringColumn(rec, "service_name")
+	versionCol, hasVersion := stringColumn(rec, "service_version")
+
+	for row := 0; row < int(rec.NumRows()); row++ {
+		id := columnValue(idCol, row)
+		rs := traces.ResourceSpans().AppendEmpty()
+		if hasName {
+			rs.Resource().Attributes().PutStr(semconvServiceName, nameCol.Value(row))
+		}
+		if hasVersion {
+			rs.Resource().Attributes().PutStr(semconvServiceVersion, versionCol.Value(row))
+		}
+		byID[id] = rs
+	}
+
+	return byID, nil
+}
+
+// decodeScopes builds one ScopeSpans per row of the scope record, attached to
+// the ResourceSpans named by its resource_id column, keyed by
+// (resource_id, scope_id) for the span record to join against.
+func decodeScopes(resources map[string]ptrace.ResourceSpans, rec arrow.Record) map[scopeKey]ptrace.ScopeSpans {
+	byKey := make(map[scopeKey]ptrace.ScopeSpans)
+
+	fallbackResource := func(resourceID string) ptrace.ResourceSpans {
+		if rs, ok := resources[resourceID]; ok {
+			return rs
+		}
+		return resources[""]
+	}
+
+	if rec == nil {
+		for resourceID, rs := range resources {
+			byKey[scopeKey{resourceID: resourceID}] = rs.ScopeSpans().AppendEmpty()
+		}
+		return byKey
+	}
+
+	resourceIDCol, _ := stringColumn(rec, "resource_id")
+	scopeIDCol, _ := stringColumn(rec, "scope_id")
+	nameCol, hasName := stringColumn(rec, "name")
+	versionCol, hasVersion := stringColumn(rec, "version")
+	schemaURLCol, hasSchemaURL := stringColumn(rec, "schema_url")
+
+	for row := 0; row < int(rec.NumRows()); row++ {
+		resourceID := columnValue(resourceIDCol, row)
+		scopeID := columnValue(scopeIDCol, row)
+
+		ss := fallbackResource(resourceID).ScopeSpans().AppendEmpty()
+		if hasName {
+			ss.Scope().SetName(nameCol.Value(row))
+		}
+		if hasVersion {
+			ss.Scope().SetVersion(versionCol.Value(row))
+		}
+		if hasSchemaURL {
+			ss.SetSchemaUrl(schemaURLCol.Value(row))
+		}
+
+		byKey[scopeKey{resourceID: resourceID, scopeID: scopeID}] = ss
+	}
+
+	return byKey
+}
+
+// decodeSpans appends one ptrace.Span per row of the span record to the
+// ScopeSpans named by its resource_id/scope_id columns, and returns every
+// decoded span keyed by its span_id so span_attrs/span_events/span_links can
+// attach to it afterwards.
+func decodeSpans(scopes map[scopeKey]ptrace.ScopeSpans, rec arrow.Record) (map[string]ptrace.Span, error) {
+	fallbackScope := func(key scopeKey) (ptrace.ScopeSpans, error) {
+		if ss, ok := scopes[key]; ok {
+			return ss, nil
+		}
+		if ss, ok := scopes[scopeKey{resourceID: key.resourceID}]; ok {
+			return ss, nil
+		}
+		for _, ss := range scopes {
+			return ss, nil
+		}
+		return ptrace.ScopeSpans{}, fmt.Errorf("span record references unknown resource_id/scope_id and no fallback scope exists")
+	}
+
+	resourceIDCol, _ := stringColumn(rec, "resource_id")
+	scopeIDCol, _ := stringColumn(rec, "scope_id")
+	spanIDCol, hasSpanID := binaryColumn(rec, "span_id")
+	parentSpanIDCol, hasParent := binaryColumn(rec, "parent_span_id")
+	traceIDCol, hasTraceID := binaryColumn(rec, "trace_id")
+	nameCol, hasName := stringColumn(rec, "name")
+	startCol, hasStart := int64Column(rec, "start_time_unix_nano")
+	endCol, hasEnd := int64Column(rec, "end_time_unix_nano")
+
+	byID := make(map[string]ptrace.Span, rec.NumRows())
+
+	for row := 0; row < int(rec.NumRows()); row++ {
+		key := scopeKey{resourceID: columnValue(resourceIDCol, row), scopeID: columnValue(scopeIDCol, row)}
+		ss, err := fallbackScope(key)
+		if err != nil {
+			return nil, err
+		}
+
+		span := ss.Spans().AppendEmpty()
+		if hasName {
+			span.SetName(nameCol.Value(row))
+		}
+		if hasTraceID && !traceIDCol.IsNull(row) {
+			var tid pcommon.TraceID
+			copy(tid[:], traceIDCol.Value(row))
+			span.SetTraceID(tid)
+		}
+		if hasStart {
+			span.SetStartTimestamp(pcommon.Timestamp(startCol.Value(row)))
+		}
+		if hasEnd {
+			span.SetEndTimestamp(pcommon.Timestamp(endCol.Value(row)))
+		}
+
+		var spanID string
+		if hasSpanID && !spanIDCol.IsNull(row) {
+			raw := spanIDCol.Value(row)
+			var sid pcommon.SpanID
+			copy(sid[:], raw)
+			span.SetSpanID(sid)
+			spanID = string(raw)
+		}
+		if hasParent && !parentSpanIDCol.IsNull(row) {
+			raw := parentSpanIDCol.Value(row)
+			var psid pcommon.SpanID
+			copy(psid[:], raw)
+			span.SetParentSpanID(psid)
+		}
+
+		if spanID != "" {
+			byID[spanID] = span
+		}
+	}
+
+	return byID, nil
+}
+
+// decodeSpanAttributes joins the span_attrs record's (span_id, key, value)
+// rows back onto the span they describe.
+func decodeSpanAttributes(spans map[string]ptrace.Span, rec arrow.Record) error {
+	if rec == nil {
+		return nil
+	}
+
+	spanIDCol, hasSpanID := binaryColumn(rec, "span_id")
+	keyCol, hasKey := stringColumn(rec, "key")
+	valueCol, hasValue := stringColumn(rec, "value")
+	if !hasSpanID || !hasKey || !hasValue {
+		return fmt.Errorf("span_attrs record is missing span_id/key/value columns")
+	}
+
+	for row := 0; row < int(rec.NumRows()); row++ {
+		span, ok := spans[string(spanIDCol.Value(row))]
+		if !ok {
+			continue
+		}
+		span.Attributes().PutStr(keyCol.Value(row), valueCol.Value(row))
+	}
+
+	return nil
+}
+
+// decodeSpanEvents joins the span_events record's (span_id, name,
+// time_unix_nano) rows back onto the span they describe.
+func decodeSpanEvents(spans map[string]ptrace.Span, rec arrow.Record) error {
+	if rec == nil {
+		return nil
+	}
+
+	spanIDCol, hasSpanID := binaryColumn(rec, "span_id")
+	nameCol, hasName := stringColumn(rec, "name")
+	timeCol, hasTime := int64Column(rec, "time_unix_nano")
+	if !hasSpanID || !hasName {
+		return fmt.Errorf("span_events record is missing span_id/name columns")
+	}
+
+	for row := 0; row < int(rec.NumRows()); row++ {
+		span, ok := spans[string(spanIDCol.Value(row))]
+		if !ok {
+			continue
+		}
+		event := span.Events().AppendEmpty()
+		event.SetName(nameCol.Value(row))
+		if hasTime {
+			event.SetTimestamp(pcommon.Timestamp(timeCol.Value(row)))
+		}
+	}
+
+	return nil
+}
+
+// decodeSpanLinks joins the span_links record's (span_id, linked_trace_id,
+// linked_span_id) rows back onto the span they describe.
+func decodeSpanLinks(spans map[string]ptrace.Span, rec arrow.Record) error {
+	if rec == nil {
+		return nil
+	}
+
+	spanIDCol, hasSpanID := binaryColumn(rec, "span_id")
+	linkedTraceIDCol, hasLinkedTraceID := binaryColumn(rec, "linked_trace_id")
+	linkedSpanIDCol, hasLinkedSpanID := binaryColumn(rec, "linked_span_id")
+	if !hasSpanID || !hasLinkedTraceID || !hasLinkedSpanID {
+		return fmt.Errorf("span_links record is missing span_id/linked_trace_id/linked_span_id columns")
+	}
+
+	for row := 0; row < int(rec.NumRows()); row++ {
+		span, ok := spans[string(spanIDCol.Value(row))]
+		if !ok {
+			continue
+		}
+
+		link := span.Links().AppendEmpty()
+		var tid pcommon.TraceID
+		copy(tid[:], linkedTraceIDCol.Value(row))
+		link.SetTraceID(tid)
+
+		var sid pcommon.SpanID
+		copy(sid[:], linkedSpanIDCol.Value(row))
+		link.SetSpanID(sid)
+	}
+
+	return nil
+}
+
+func stringColumn(rec arrow.Record, name string) (*array.String, bool) {
+	indices := rec.Schema().FieldIndices(name)
+	if len(indices) == 0 {
+		return nil, false
+	}
+	col, ok := rec.Column(indices[0]).(*array.String)
+	return col, ok
+}
+
+func binaryColumn(rec arrow.Record, name string) (*array.Binary, bool) {
+	indices := rec.Schema().FieldIndices(name)
+	if len(indices) == 0 {
+		return nil, false
+	}
+	col, ok := rec.Column(indices[0]).(*array.Binary)
+	return col, ok
+}
+
+func int64Column(rec arrow.Record, name string) (*array.Int64, bool) {
+	indices := rec.Schema().FieldIndices(name)
+	if len(indices) == 0 {
+		return nil, false
+	}
+	col, ok := rec.Column(indices[0]).(*array.Int64)
+	return col, ok
+}
+
+// columnValue reads a string column's value, treating a missing column as
+// the empty string so joins degrade gracefully instead of panicking.
+func columnValue(col *array.String, row int) string {
+	if col == nil {
+		return ""
+	}
+	return col.Value(row)
+}
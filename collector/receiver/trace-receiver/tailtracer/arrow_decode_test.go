@@ -0,0 +1,213 @@
+package tailtracer
+
+import (
+	"testing"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestJoinRecordsIntoTraces builds one resource, one scope and two spans (a
+// root and a child, joined via parent_span_id) plus a span_attrs, a
+// span_events and a span_links row, and checks that joinRecordsIntoTraces
+// reassembles them into the matching ptrace.Traces shape instead of
+// discarding the decoded columns.
+func TestJoinRecordsIntoTraces(t *testing.T) {
+	rootSpanID := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	childSpanID := []byte{8, 7, 6, 5, 4, 3, 2, 1}
+	traceID := []byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+
+	records := map[string]arrow.Record{
+		"resource":    buildResourceRecord(t),
+		"scope":       buildScopeRecord(t),
+		"span":        buildSpanRecord(t, traceID, rootSpanID, childSpanID),
+		"span_attrs":  buildSpanAttrsRecord(t, rootSpanID),
+		"span_events": buildSpanEventsRecord(t, rootSpanID),
+		"span_links":  buildSpanLinksRecord(t, childSpanID, traceID, rootSpanID),
+	}
+
+	traces, err := joinRecordsIntoTraces(records)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, traces.ResourceSpans().Len())
+	rs := traces.ResourceSpans().At(0)
+	serviceName, ok := rs.Resource().Attributes().Get(semconvServiceName)
+	require.True(t, ok)
+	assert.Equal(t, "atm-service", serviceName.Str())
+
+	require.Equal(t, 1, rs.ScopeSpans().Len())
+	ss := rs.ScopeSpans().At(0)
+	assert.Equal(t, "atmscope", ss.Scope().Name())
+
+	require.Equal(t, 2, ss.Spans().Len())
+	root, child := ss.Spans().At(0), ss.Spans().At(1)
+
+	assert.Equal(t, "atm-withdraw", root.Name())
+	assert.True(t, root.ParentSpanID().IsEmpty())
+	attr, ok := root.Attributes().Get("atm.id")
+	require.True(t, ok)
+	assert.Equal(t, "42", attr.Str())
+	require.Equal(t, 1, root.Events().Len())
+	assert.Equal(t, "card-read", root.Events().At(0).Name())
+	require.Equal(t, 1, child.Links().Len())
+
+	assert.Equal(t, "backend-authorize", child.Name())
+	assert.Equal(t, root.SpanID(), child.ParentSpanID())
+}
+
+// TestJoinRecordsIntoTracesRejectsAmbiguousResources checks that a resource
+// record covering more than one row without a resource_id column is rejected
+// instead of silently collapsing every row onto the same "" key.
+func TestJoinRecordsIntoTracesRejectsAmbiguousResources(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "service_name", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer b.Release()
+	names := b.Field(0).(*array.StringBuilder)
+	names.Append("atm-service")
+	names.Append("backend-service")
+	resourceRecord := b.NewRecord()
+
+	records := map[string]arrow.Record{
+		"resource": resourceRecord,
+		"span":     buildSpanRecord(t, []byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, []byte{1, 2, 3, 4, 5, 6, 7, 8}, []byte{8, 7, 6, 5, 4, 3, 2, 1}),
+	}
+
+	_, err := joinRecordsIntoTraces(records)
+	require.Error(t, err)
+}
+
+func buildResourceRecord(t *testing.T) arrow.Record {
+	t.Helper()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "resource_id", Type: arrow.BinaryTypes.String},
+		{Name: "service_name", Type: arrow.BinaryTypes.String},
+		{Name: "service_version", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer b.Release()
+	b.Field(0).(*array.StringBuilder).Append("r1")
+	b.Field(1).(*array.StringBuilder).Append("atm-service")
+	b.Field(2).(*array.StringBuilder).Append("1.0.0")
+	return b.NewRecord()
+}
+
+func buildScopeRecord(t *testing.T) arrow.Record {
+	t.Helper()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "resource_id", Type: arrow.BinaryTypes.String},
+		{Name: "scope_id", Type: arrow.BinaryTypes.String},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "version", Type: arrow.BinaryTypes.String},
+		{Name: "schema_url", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer b.Release()
+	b.Field(0).(*array.StringBuilder).Append("r1")
+	b.Field(1).(*array.StringBuilder).Append("s1")
+	b.Field(2).(*array.StringBuilder).Append("atmscope")
+	b.Field(3).(*array.StringBuilder).Append("0.1.0")
+	b.Field(4).(*array.StringBuilder).Append("https://example.com/schema")
+	return b.NewRecord()
+}
+
+func buildSpanRecord(t *testing.T, traceID, rootSpanID, childSpanID []byte) arrow.Record {
+	t.Helper()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "resource_id", Type: arrow.BinaryTypes.String},
+		{Name: "scope_id", Type: arrow.BinaryTypes.String},
+		{Name: "span_id", Type: arrow.BinaryTypes.Binary},
+		{Name: "parent_span_id", Type: arrow.BinaryTypes.Binary, Nullable: true},
+		{Name: "trace_id", Type: arrow.BinaryTypes.Binary},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "start_time_unix_nano", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "end_time_unix_nano", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer b.Release()
+
+	resourceIDs := b.Field(0).(*array.StringBuilder)
+	scopeIDs := b.Field(1).(*array.StringBuilder)
+	spanIDs := b.Field(2).(*array.BinaryBuilder)
+	parentSpanIDs := b.Field(3).(*array.BinaryBuilder)
+	traceIDs := b.Field(4).(*array.BinaryBuilder)
+	names := b.Field(5).(*array.StringBuilder)
+	starts := b.Field(6).(*array.Int64Builder)
+	ends := b.Field(7).(*array.Int64Builder)
+
+	resourceIDs.Append("r1")
+	scopeIDs.Append("s1")
+	spanIDs.Append(rootSpanID)
+	parentSpanIDs.AppendNull()
+	traceIDs.Append(traceID)
+	names.Append("atm-withdraw")
+	starts.Append(1)
+	ends.Append(2)
+
+	resourceIDs.Append("r1")
+	scopeIDs.Append("s1")
+	spanIDs.Append(childSpanID)
+	parentSpanIDs.Append(rootSpanID)
+	traceIDs.Append(traceID)
+	names.Append("backend-authorize")
+	starts.Append(2)
+	ends.Append(3)
+
+	return b.NewRecord()
+}
+
+func buildSpanAttrsRecord(t *testing.T, spanID []byte) arrow.Record {
+	t.Helper()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "span_id", Type: arrow.BinaryTypes.Binary},
+		{Name: "key", Type: arrow.BinaryTypes.String},
+		{Name: "value", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer b.Release()
+	b.Field(0).(*array.BinaryBuilder).Append(spanID)
+	b.Field(1).(*array.StringBuilder).Append("atm.id")
+	b.Field(2).(*array.StringBuilder).Append("42")
+	return b.NewRecord()
+}
+
+func buildSpanEventsRecord(t *testing.T, spanID []byte) arrow.Record {
+	t.Helper()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "span_id", Type: arrow.BinaryTypes.Binary},
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "time_unix_nano", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer b.Release()
+	b.Field(0).(*array.BinaryBuilder).Append(spanID)
+	b.Field(1).(*array.StringBuilder).Append("card-read")
+	b.Field(2).(*array.Int64Builder).Append(1)
+	return b.NewRecord()
+}
+
+func buildSpanLinksRecord(t *testing.T, spanID, linkedTraceID, linkedSpanID []byte) arrow.Record {
+	t.Helper()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "span_id", Type: arrow.BinaryTypes.Binary},
+		{Name: "linked_trace_id", Type: arrow.BinaryTypes.Binary},
+		{Name: "linked_span_id", Type: arrow.BinaryTypes.Binary},
+	}, nil)
+
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer b.Release()
+	b.Field(0).(*array.BinaryBuilder).Append(spanID)
+	b.Field(1).(*array.BinaryBuilder).Append(linkedTraceID)
+	b.Field(2).(*array.BinaryBuilder).Append(linkedSpanID)
+	return b.NewRecord()
+}
@@ -0,0 +1,178 @@
+package tailtracer
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/ipc"
+	"github.com/klauspost/compress/zstd"
+	arrowpb "github.com/open-telemetry/otel-arrow/api/experimental/arrow/v1"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+// arrowStreamServer implements arrowpb.ArrowTracesServiceServer's
+// bidirectional stream: clients send BatchArrowRecords carrying IPC-encoded
+// Arrow RecordBatches for the resource/scope/span/span_attrs/span_events/
+// span_links schemas, and the server acknowledges each batch with a
+// BatchStatus.
+type arrowStreamServer struct {
+	arrowpb.UnimplementedArrowTracesServiceServer
+
+	cfg      *ArrowConfig
+	logger   *zap.Logger
+	consumer consumer.Traces
+	zstdDec  *zstd.Decoder
+
+	memMu    sync.Mutex
+	memInUse int64
+}
+
+func newArrowStreamServer(cfg *ArrowConfig, logger *zap.Logger, next consumer.Traces) *arrowStreamServer {
+	s := &arrowStreamServer{cfg: cfg, logger: logger, consumer: next}
+	if cfg.ZstdLevel > 0 {
+		// Decompression doesn't depend on the level the encoder chose; one
+		// decoder is reused across the stream's batches.
+		dec, err := zstd.NewReader(nil)
+		if err != nil {
+			logger.Error("failed to create zstd decoder, arrow payloads will be read uncompressed", zap.Error(err))
+		} else {
+			s.zstdDec = dec
+		}
+	}
+	return s
+}
+
+// ArrowTraces handles one bidirectional stream for the lifetime of a client
+// connection, maintaining a per-stream schema cache keyed by each payload's
+// schema_id. Every payload still carries its own Arrow schema; the cached
+// schema only makes a later payload fail to decode when its schema differs
+// from the one seen earlier for the same schema_id.
+func (s *arrowStreamServer) ArrowTraces(stream arrowpb.ArrowTracesService_ArrowTracesServer) error {
+	cache := newStreamSchemaCache()
+
+	for {
+		batch, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		traces, decodeErr := s.decodeBatch(cache, batch)
+		status := &arrowpb.BatchStatus{BatchId: batch.BatchId}
+
+		switch {
+		case decodeErr != nil:
+			s.logger.Warn("failed to decode arrow batch", zap.String("batch_id", batch.BatchId), zap.Error(decodeErr))
+			status.StatusCode = arrowpb.StatusCode_INVALID_ARGUMENT
+			status.RetryInfo = &arrowpb.RetryInfo{RetryDelaySeconds: 1}
+		case s.consumer.ConsumeTraces(stream.Context(), traces) != nil:
+			status.StatusCode = arrowpb.StatusCode_UNAVAILABLE
+			status.RetryInfo = &arrowpb.RetryInfo{RetryDelaySeconds: 5}
+		default:
+			status.StatusCode = arrowpb.StatusCode_OK
+		}
+
+		if err := stream.Send(status); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeBatch decodes the resource/scope/span/span_attrs/span_events/span_links
+// IPC record batches in a BatchArrowRecords message and joins spans to their
+// parents via span_id/parent_span_id to rebuild ptrace.Traces.
+func (s *arrowStreamServer) decodeBatch(cache *streamSchemaCache, batch *arrowpb.BatchArrowRecords) (ptrace.Traces, error) {
+	payloads, size, err := s.decompressPayloads(batch)
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+
+	if err := s.admit(size); err != nil {
+		return ptrace.Traces{}, err
+	}
+	defer s.release(size)
+
+	records := make(map[string]arrow.Record, len(payloads))
+	for schemaID, raw := range payloads {
+		reader, err := ipc.NewReader(bytes.NewReader(raw), ipc.WithSchema(cache.schemaFor(schemaID)))
+		if err != nil {
+			return ptrace.Traces{}, fmt.Errorf("decoding %s record batch: %w", schemaID, err)
+		}
+		for reader.Next() {
+			records[schemaID] = reader.Record()
+		}
+		cache.put(schemaID, reader.Schema())
+		reader.Release()
+	}
+
+	return joinRecordsIntoTraces(records)
+}
+
+// decompressPayloads zstd-decompresses (when configured) every Arrow IPC
+// payload in batch up front and reports their total decoded size, so
+// admission control in decodeBatch charges memory_limit_mib against the
+// Arrow memory actually held in process rather than the smaller compressed
+// byte count that crossed the wire.
+func (s *arrowStreamServer) decompressPayloads(batch *arrowpb.BatchArrowRecords) (map[string][]byte, int64, error) {
+	payloads := make(map[string][]byte, len(batch.ArrowPayloads))
+	var total int64
+
+	for _, payload := range batch.ArrowPayloads {
+		raw := payload.Record
+		if s.zstdDec != nil {
+			decompressed, err := s.zstdDec.DecodeAll(raw, nil)
+			if err != nil {
+				return nil, 0, fmt.Errorf("zstd-decompressing %s record batch: %w", payload.SchemaId, err)
+			}
+			raw = decompressed
+		}
+		payloads[payload.SchemaId] = raw
+		total += int64(len(raw))
+	}
+
+	return payloads, total, nil
+}
+
+func (s *arrowStreamServer) admit(sizeBytes int64) error {
+	s.memMu.Lock()
+	defer s.memMu.Unlock()
+
+	limit := int64(s.cfg.MemoryLimitMiB) * 1024 * 1024
+	if s.memInUse+sizeBytes > limit {
+		return fmt.Errorf("rejecting arrow batch: in-flight memory %d bytes would exceed memory_limit_mib (%d MiB)", s.memInUse+sizeBytes, s.cfg.MemoryLimitMiB)
+	}
+	s.memInUse += sizeBytes
+	return nil
+}
+
+func (s *arrowStreamServer) release(sizeBytes int64) {
+	s.memMu.Lock()
+	defer s.memMu.Unlock()
+	s.memInUse -= sizeBytes
+}
+
+// streamSchemaCache remembers the Arrow schema last decoded for each
+// schema_id seen on a stream.
+type streamSchemaCache struct {
+	mu      sync.Mutex
+	schemas map[string]*arrow.Schema
+}
+
+func newStreamSchemaCache() *streamSchemaCache {
+	return &streamSchemaCache{schemas: make(map[string]*arrow.Schema)}
+}
+
+func (c *streamSchemaCache) schemaFor(schemaID string) *arrow.Schema {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.schemas[schemaID]
+}
+
+func (c *streamSchemaCache) put(schemaID string, schema *arrow.Schema) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schemas[schemaID] = schema
+}
@@ -0,0 +1,185 @@
+package tailtracer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/ipc"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/klauspost/compress/zstd"
+	arrowpb "github.com/open-telemetry/otel-arrow/api/experimental/arrow/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// fakeArrowStream is a minimal arrowpb.ArrowTracesService_ArrowTracesServer
+// double: it replays a fixed sequence of inbound batches and records every
+// BatchStatus the server sends back, so tests can drive ArrowTraces without a
+// real gRPC connection.
+type fakeArrowStream struct {
+	grpc.ServerStream
+
+	batches []*arrowpb.BatchArrowRecords
+	idx     int
+	sent    []*arrowpb.BatchStatus
+}
+
+func newFakeArrowStream(batches ...*arrowpb.BatchArrowRecords) *fakeArrowStream {
+	return &fakeArrowStream{batches: batches}
+}
+
+func (f *fakeArrowStream) Context() context.Context { return context.Background() }
+
+func (f *fakeArrowStream) Send(status *arrowpb.BatchStatus) error {
+	f.sent = append(f.sent, status)
+	return nil
+}
+
+func (f *fakeArrowStream) Recv() (*arrowpb.BatchArrowRecords, error) {
+	if f.idx >= len(f.batches) {
+		return nil, io.EOF
+	}
+	batch := f.batches[f.idx]
+	f.idx++
+	return batch, nil
+}
+
+// encodeIPCPayload IPC-encodes rec as the sole record batch of schemaID,
+// optionally zstd-compressing it to the given level, mirroring what a real
+// Arrow client sends over the wire.
+func encodeIPCPayload(t *testing.T, schemaID string, rec arrow.Record, zstdLevel int) *arrowpb.ArrowPayload {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(rec.Schema()))
+	require.NoError(t, w.Write(rec))
+	require.NoError(t, w.Close())
+
+	raw := buf.Bytes()
+	if zstdLevel > 0 {
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(zstdLevel)))
+		require.NoError(t, err)
+		raw = enc.EncodeAll(raw, nil)
+		require.NoError(t, enc.Close())
+	}
+
+	return &arrowpb.ArrowPayload{SchemaId: schemaID, Record: raw}
+}
+
+func buildMinimalSpanRecord(t *testing.T, name string) arrow.Record {
+	t.Helper()
+	schema := arrow.NewSchema([]arrow.Field{{Name: "name", Type: arrow.BinaryTypes.String}}, nil)
+	b := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer b.Release()
+	b.Field(0).(*array.StringBuilder).Append(name)
+	return b.NewRecord()
+}
+
+// TestArrowTracesStatusCodes checks that ArrowTraces maps a well-formed
+// batch to StatusCode_OK, a batch that fails to decode to
+// StatusCode_INVALID_ARGUMENT, and a batch the consumer rejects to
+// StatusCode_UNAVAILABLE.
+func TestArrowTracesStatusCodes(t *testing.T) {
+	validPayload := encodeIPCPayload(t, "span", buildMinimalSpanRecord(t, "atm-withdraw"), 0)
+
+	t.Run("ok", func(t *testing.T) {
+		s := newArrowStreamServer(&ArrowConfig{MemoryLimitMiB: 16}, zap.NewNop(), consumertest.NewNop())
+		stream := newFakeArrowStream(&arrowpb.BatchArrowRecords{
+			BatchId:       "b1",
+			ArrowPayloads: []*arrowpb.ArrowPayload{validPayload},
+		})
+
+		assert.Equal(t, io.EOF, s.ArrowTraces(stream))
+		require.Len(t, stream.sent, 1)
+		assert.Equal(t, arrowpb.StatusCode_OK, stream.sent[0].StatusCode)
+	})
+
+	t.Run("invalid argument on malformed payload", func(t *testing.T) {
+		s := newArrowStreamServer(&ArrowConfig{MemoryLimitMiB: 16}, zap.NewNop(), consumertest.NewNop())
+		malformed := &arrowpb.ArrowPayload{SchemaId: "span", Record: []byte("not a valid arrow IPC stream")}
+		stream := newFakeArrowStream(&arrowpb.BatchArrowRecords{
+			BatchId:       "b1",
+			ArrowPayloads: []*arrowpb.ArrowPayload{malformed},
+		})
+
+		assert.Equal(t, io.EOF, s.ArrowTraces(stream))
+		require.Len(t, stream.sent, 1)
+		assert.Equal(t, arrowpb.StatusCode_INVALID_ARGUMENT, stream.sent[0].StatusCode)
+	})
+
+	t.Run("unavailable when consumer rejects", func(t *testing.T) {
+		s := newArrowStreamServer(&ArrowConfig{MemoryLimitMiB: 16}, zap.NewNop(), consumertest.NewErr(errors.New("pipeline full")))
+		stream := newFakeArrowStream(&arrowpb.BatchArrowRecords{
+			BatchId:       "b1",
+			ArrowPayloads: []*arrowpb.ArrowPayload{validPayload},
+		})
+
+		assert.Equal(t, io.EOF, s.ArrowTraces(stream))
+		require.Len(t, stream.sent, 1)
+		assert.Equal(t, arrowpb.StatusCode_UNAVAILABLE, stream.sent[0].StatusCode)
+	})
+}
+
+// TestArrowTracesReusesSchemaCacheAcrossBatches sends two batches for the
+// same schema_id over one stream and checks both are accepted, exercising
+// the per-stream schema cache: the second batch's schema is checked against
+// the one cached from the first.
+func TestArrowTracesReusesSchemaCacheAcrossBatches(t *testing.T) {
+	s := newArrowStreamServer(&ArrowConfig{MemoryLimitMiB: 16}, zap.NewNop(), consumertest.NewNop())
+	stream := newFakeArrowStream(
+		&arrowpb.BatchArrowRecords{BatchId: "b1", ArrowPayloads: []*arrowpb.ArrowPayload{
+			encodeIPCPayload(t, "span", buildMinimalSpanRecord(t, "atm-withdraw"), 0),
+		}},
+		&arrowpb.BatchArrowRecords{BatchId: "b2", ArrowPayloads: []*arrowpb.ArrowPayload{
+			encodeIPCPayload(t, "span", buildMinimalSpanRecord(t, "backend-authorize"), 0),
+		}},
+	)
+
+	assert.Equal(t, io.EOF, s.ArrowTraces(stream))
+	require.Len(t, stream.sent, 2)
+	assert.Equal(t, arrowpb.StatusCode_OK, stream.sent[0].StatusCode)
+	assert.Equal(t, arrowpb.StatusCode_OK, stream.sent[1].StatusCode)
+}
+
+// TestStreamSchemaCachePutAndGet checks the cache itself, outside any stream:
+// an unseen schema_id yields nil, and a schema put under a schema_id is
+// returned for it.
+func TestStreamSchemaCachePutAndGet(t *testing.T) {
+	cache := newStreamSchemaCache()
+	assert.Nil(t, cache.schemaFor("span"))
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "name", Type: arrow.BinaryTypes.String}}, nil)
+	cache.put("span", schema)
+
+	assert.Same(t, schema, cache.schemaFor("span"))
+}
+
+// TestDecodeBatchAdmissionChargesDecompressedSize builds a batch whose
+// zstd-compressed wire size fits comfortably under a 1 MiB limit but whose
+// decompressed Arrow payload does not, and checks decodeBatch rejects it -
+// proving memory_limit_mib is charged against the decoded bytes held in
+// process, not the smaller compressed bytes that crossed the wire.
+func TestDecodeBatchAdmissionChargesDecompressedSize(t *testing.T) {
+	hugeRecord := buildMinimalSpanRecord(t, strings.Repeat("a", 2*1024*1024))
+	payload := encodeIPCPayload(t, "span", hugeRecord, 3)
+	require.Less(t, len(payload.Record), 1024*1024, "fixture is invalid: compressed payload should be well under 1 MiB")
+
+	batch := &arrowpb.BatchArrowRecords{BatchId: "b1", ArrowPayloads: []*arrowpb.ArrowPayload{payload}}
+
+	s := newArrowStreamServer(&ArrowConfig{MemoryLimitMiB: 1, ZstdLevel: 3}, zap.NewNop(), consumertest.NewNop())
+	_, err := s.decodeBatch(newStreamSchemaCache(), batch)
+	require.Error(t, err, "admission control should reject a batch whose decompressed size exceeds memory_limit_mib")
+
+	s = newArrowStreamServer(&ArrowConfig{MemoryLimitMiB: 8, ZstdLevel: 3}, zap.NewNop(), consumertest.NewNop())
+	_, err = s.decodeBatch(newStreamSchemaCache(), batch)
+	require.NoError(t, err)
+}
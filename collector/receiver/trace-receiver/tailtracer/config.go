@@ -4,13 +4,159 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/mitchellh/mapstructure"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/confmap"
+	"go.uber.org/multierr"
 )
 
+// MinInterval is the smallest Interval the receiver will accept. It is a
+// package var, rather than a constant, so integration tests can lower it
+// (e.g. to 1ms) without forking the receiver.
+var MinInterval = time.Second
+
 // Config represents the receiver config settings within the collector's config.yaml
 type Config struct {
-   config.ReceiverSettings `mapstructure:",squash"`
-   Interval       string `mapstructure:"interval"`
-   NumberOfTraces int `mapstructure:"number_of_traces"`
+	config.ReceiverSettings `mapstructure:",squash"`
+	Interval                time.Duration `mapstructure:"interval"`
+	NumberOfTraces          int           `mapstructure:"number_of_traces"`
+
+	// Protocols configures the inbound OTLP servers that feed real traces into
+	// the receiver. When left unset, the receiver falls back to synthesizing
+	// traces on Interval/NumberOfTraces instead.
+	Protocols *Protocols `mapstructure:"protocols"`
+
+	// Arrow enables the Arrow-encoded batched streaming ingestion mode,
+	// an alternative to the OTLP/protobuf transports above.
+	Arrow *ArrowConfig `mapstructure:"arrow"`
+
+	// osConfig holds the fields that only make sense on a single platform,
+	// e.g. Cgroup on linux or EventLogChannel on windows. See config_linux.go,
+	// config_windows.go and config_other.go.
+	osConfig `mapstructure:",squash"`
+
+	// Resources describes the services the synthetic generator should model.
+	// When set, each generated trace walks the resources in order, emitting
+	// one ResourceSpans per profile and threading parent-child spans across
+	// them to approximate the repo's Atm->BackendSystem call graph. When
+	// empty, the generator falls back to the single anonymous resource it
+	// has always produced.
+	Resources []ResourceProfile `mapstructure:"resources"`
+}
+
+// ResourceProfile describes one simulated service: the resource attributes
+// its spans report, and the instrumentation scopes those spans are grouped
+// under.
+type ResourceProfile struct {
+	ServiceName    string `mapstructure:"service_name"`
+	ServiceVersion string `mapstructure:"service_version"`
+	CloudProvider  string `mapstructure:"cloud_provider"`
+	CloudRegion    string `mapstructure:"cloud_region"`
+
+	Scopes []ScopeProfile `mapstructure:"scopes"`
+}
+
+// ScopeProfile describes one instrumentation scope a resource's spans are
+// attributed to.
+type ScopeProfile struct {
+	Name       string            `mapstructure:"name"`
+	Version    string            `mapstructure:"version"`
+	SchemaURL  string            `mapstructure:"schema_url"`
+	Attributes map[string]string `mapstructure:"attributes"`
+}
+
+// ArrowConfig configures the bidirectional gRPC stream that accepts
+// BatchArrowRecords messages and decodes them into ptrace.Traces.
+type ArrowConfig struct {
+	// MemoryLimitMiB bounds in-flight Arrow record batch memory; batches
+	// received while over the limit are rejected for admission control.
+	MemoryLimitMiB int `mapstructure:"memory_limit_mib"`
+
+	// MaxStreamLifetime forces a stream to rotate after this long, so
+	// load balancers can redistribute long-lived connections.
+	MaxStreamLifetime time.Duration `mapstructure:"max_stream_lifetime"`
+
+	// ZstdLevel is the zstd compression level (1-22) used for the Arrow IPC
+	// payloads carried over the stream.
+	ZstdLevel int `mapstructure:"zstd_level"`
 }
 
+// Protocols mirrors the otlpreceiver idiom of a protocols: grpc: / http: block,
+// letting operators expose either or both transports on their own endpoints.
+type Protocols struct {
+	GRPC *configgrpc.GRPCServerSettings `mapstructure:"grpc"`
+	HTTP *confighttp.HTTPServerSettings `mapstructure:"http"`
+}
+
+// Unmarshal decodes conf into cfg, teaching mapstructure how to turn a YAML
+// duration string like "30s" directly into Interval's time.Duration.
+func (cfg *Config) Unmarshal(conf *confmap.Conf) error {
+	return conf.Unmarshal(cfg, confmap.WithDecodeHookFunc(mapstructure.StringToTimeDurationHookFunc()))
+}
+
+// Validate checks that the receiver configuration is self consistent. It
+// joins every violation it finds with multierr rather than returning on the
+// first one, so a single misconfigured collector.yaml reports everything
+// wrong with it at once.
+func (cfg *Config) Validate() error {
+	var errs error
+
+	if cfg.Protocols != nil {
+		if cfg.Protocols.GRPC == nil && cfg.Protocols.HTTP == nil {
+			errs = multierr.Append(errs, fmt.Errorf("%w: protocols section must configure at least one of grpc or http", ErrMissingEndpoint))
+		}
+		if cfg.Protocols.GRPC != nil && cfg.Protocols.HTTP != nil &&
+			cfg.Protocols.GRPC.NetAddr.Endpoint == cfg.Protocols.HTTP.Endpoint {
+			errs = multierr.Append(errs, fmt.Errorf("grpc and http protocols cannot both listen on %q", cfg.Protocols.GRPC.NetAddr.Endpoint))
+		}
+	}
+
+	if cfg.NumberOfTraces == 0 && cfg.Protocols == nil && cfg.Arrow == nil {
+		errs = multierr.Append(errs, fmt.Errorf("%w: must configure at least one protocol when number_of_traces is 0", ErrMissingEndpoint))
+	}
+	if cfg.NumberOfTraces < 0 {
+		errs = multierr.Append(errs, fmt.Errorf("%w: got %d", ErrNumberOfTracesInvalid, cfg.NumberOfTraces))
+	}
+
+	if cfg.NumberOfTraces > 0 && cfg.Interval < MinInterval {
+		errs = multierr.Append(errs, fmt.Errorf("%w: interval %s is below the minimum of %s", ErrIntervalTooSmall, cfg.Interval, MinInterval))
+	}
 
+	if cfg.Arrow != nil {
+		if cfg.Protocols == nil || cfg.Protocols.GRPC == nil {
+			errs = multierr.Append(errs, fmt.Errorf("%w: arrow requires protocols.grpc to be configured, since it streams over the same gRPC server", ErrMissingEndpoint))
+		}
+		if cfg.Arrow.MemoryLimitMiB < 1 {
+			errs = multierr.Append(errs, fmt.Errorf("arrow.memory_limit_mib must be at least 1, got %d", cfg.Arrow.MemoryLimitMiB))
+		}
+		if cfg.Arrow.ZstdLevel < 1 || cfg.Arrow.ZstdLevel > 22 {
+			errs = multierr.Append(errs, fmt.Errorf("arrow.zstd_level must be between 1 and 22, got %d", cfg.Arrow.ZstdLevel))
+		}
+	}
+
+	errs = multierr.Append(errs, validateResources(cfg.Resources))
+	errs = multierr.Append(errs, validateOSSpecific(cfg))
+
+	return errs
+}
+
+func validateResources(resources []ResourceProfile) error {
+	var errs error
+	for _, resource := range resources {
+		if resource.ServiceName == "" {
+			errs = multierr.Append(errs, fmt.Errorf("resources: service_name must not be empty"))
+		}
+
+		seenScopes := make(map[string]struct{}, len(resource.Scopes))
+		for _, scope := range resource.Scopes {
+			if _, ok := seenScopes[scope.Name]; ok {
+				errs = multierr.Append(errs, fmt.Errorf("resources: duplicate scope name %q for service %q", scope.Name, resource.ServiceName))
+			}
+			seenScopes[scope.Name] = struct{}{}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,19 @@
+//go:build linux
+
+package tailtracer
+
+import "fmt"
+
+// osConfig holds the linux-specific receiver settings.
+type osConfig struct {
+	// Cgroup scopes the synthetic ATM simulation to processes under this
+	// cgroup path, e.g. "/sys/fs/cgroup/atm-sim".
+	Cgroup string `mapstructure:"cgroup"`
+}
+
+func validateOSSpecific(cfg *Config) error {
+	if cfg.Cgroup != "" && cfg.Cgroup[0] != '/' {
+		return fmt.Errorf("cgroup must be an absolute path, got %q", cfg.Cgroup)
+	}
+	return nil
+}
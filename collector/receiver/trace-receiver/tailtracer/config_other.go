@@ -0,0 +1,11 @@
+//go:build !linux && !windows
+
+package tailtracer
+
+// osConfig is empty on platforms with no OS-specific receiver settings; it
+// only exists so Config can squash-embed it uniformly across GOOS.
+type osConfig struct{}
+
+func validateOSSpecific(_ *Config) error {
+	return nil
+}
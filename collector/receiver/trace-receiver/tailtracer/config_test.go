@@ -0,0 +1,57 @@
+package tailtracer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+// TestUnmarshalInterval covers both the legacy "30s" string YAML form and a
+// confmap-native integer-nanoseconds form, guarding against a regression the
+// next time the upstream collector config API is renamed or reshaped (as
+// happened with configmodels -> config).
+func TestUnmarshalInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  map[string]any
+		want time.Duration
+	}{
+		{
+			name: "string duration",
+			raw:  map[string]any{"interval": "30s", "number_of_traces": 10},
+			want: 30 * time.Second,
+		},
+		{
+			name: "duration as nanoseconds",
+			raw:  map[string]any{"interval": int64(30 * time.Second), "number_of_traces": 10},
+			want: 30 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			require.NoError(t, cfg.Unmarshal(confmap.NewFromStringMap(tt.raw)))
+			assert.Equal(t, tt.want, cfg.Interval)
+		})
+	}
+}
+
+func TestValidateIntervalTooSmall(t *testing.T) {
+	originalMin := MinInterval
+	MinInterval = time.Millisecond
+	defer func() { MinInterval = originalMin }()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Interval = 0
+	require.ErrorIs(t, cfg.Validate(), ErrIntervalTooSmall)
+}
+
+func TestValidateNumberOfTracesInvalid(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.NumberOfTraces = -1
+	require.ErrorIs(t, cfg.Validate(), ErrNumberOfTracesInvalid)
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package tailtracer
+
+// osConfig holds the windows-specific receiver settings.
+type osConfig struct {
+	// EventLogChannel names the Windows Event Log channel that synthetic
+	// BackendSystem activity is tagged with.
+	EventLogChannel string `mapstructure:"event_log_channel"`
+}
+
+func validateOSSpecific(_ *Config) error {
+	return nil
+}
@@ -0,0 +1,15 @@
+package tailtracer
+
+import "errors"
+
+var (
+	// ErrIntervalTooSmall is returned when Interval is set below MinInterval.
+	ErrIntervalTooSmall = errors.New("interval is too small")
+
+	// ErrNumberOfTracesInvalid is returned when NumberOfTraces is negative.
+	ErrNumberOfTracesInvalid = errors.New("number_of_traces is invalid")
+
+	// ErrMissingEndpoint is returned when the receiver has no way to produce
+	// traces: no protocol, no arrow stream, and no synthetic generator.
+	ErrMissingEndpoint = errors.New("missing endpoint")
+)
@@ -0,0 +1,41 @@
+package tailtracer
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+)
+
+const (
+	typeStr         = "tailtracer"
+	defaultInterval = 5 * time.Second
+)
+
+// NewFactory creates a factory for the tailtracer receiver.
+func NewFactory() component.ReceiverFactory {
+	return component.NewReceiverFactory(
+		typeStr,
+		createDefaultConfig,
+		component.WithTracesReceiver(createTracesReceiver))
+}
+
+func createDefaultConfig() config.Receiver {
+	return &Config{
+		ReceiverSettings: config.NewReceiverSettings(config.NewComponentID(typeStr)),
+		Interval:         defaultInterval,
+		NumberOfTraces:   10,
+	}
+}
+
+func createTracesReceiver(
+	_ context.Context,
+	set component.ReceiverCreateSettings,
+	cfg config.Receiver,
+	nextConsumer consumer.Traces,
+) (component.TracesReceiver, error) {
+	rCfg := cfg.(*Config)
+	return newTailTracerReceiver(rCfg, set, nextConsumer)
+}
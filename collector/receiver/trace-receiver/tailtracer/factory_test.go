@@ -0,0 +1,36 @@
+package tailtracer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+// TestCreateTracesReceiver exercises the factory's default config path and a
+// full Start/Shutdown cycle on every GOOS, since the journald receiver once
+// regressed on a platform that never ran this test.
+func TestCreateTracesReceiver(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+
+	receiver, err := createTracesReceiver(
+		context.Background(),
+		componenttest.NewNopReceiverCreateSettings(),
+		cfg,
+		consumertest.NewNop(),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, receiver)
+
+	assert.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, receiver.Shutdown(context.Background()))
+}
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+}
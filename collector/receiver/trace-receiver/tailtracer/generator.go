@@ -0,0 +1,113 @@
+package tailtracer
+
+import (
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	semconvServiceName    = "service.name"
+	semconvServiceVersion = "service.version"
+	semconvCloudProvider  = "cloud.provider"
+	semconvCloudRegion    = "cloud.region"
+)
+
+// generateTraces produces NumberOfTraces traces to stand in for the ATM/
+// BackendSystem activity this receiver simulates. When Resources is
+// configured, every trace walks the resource profiles in order, emitting one
+// ResourceSpans per profile with parent-child spans chained across them;
+// otherwise it falls back to the single anonymous resource this receiver has
+// always produced.
+func (r *tailTracerReceiver) generateTraces() ptrace.Traces {
+	if len(r.cfg.Resources) == 0 {
+		return r.generateAnonymousTraces()
+	}
+
+	traces := ptrace.NewTraces()
+	for i := 0; i < r.cfg.NumberOfTraces; i++ {
+		traceID := randTraceID()
+		var parentSpanID pcommon.SpanID
+
+		for _, resource := range r.cfg.Resources {
+			rs := traces.ResourceSpans().AppendEmpty()
+			populateResource(rs.Resource(), resource)
+
+			for _, scope := range resource.Scopes {
+				ss := rs.ScopeSpans().AppendEmpty()
+				ss.SetSchemaUrl(scope.SchemaURL)
+				populateScope(ss.Scope(), scope)
+
+				span := ss.Spans().AppendEmpty()
+				span.SetName(resource.ServiceName + "." + scope.Name)
+				span.SetTraceID(traceID)
+				span.SetSpanID(randSpanID())
+				if parentSpanID != (pcommon.SpanID{}) {
+					span.SetParentSpanID(parentSpanID)
+				}
+				span.SetStartTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+				span.SetEndTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+
+				// Chain the next span, in this resource's next scope or the
+				// next resource, off of this one, modeling the
+				// Atm->BackendSystem call graph.
+				parentSpanID = span.SpanID()
+			}
+		}
+	}
+
+	return traces
+}
+
+func (r *tailTracerReceiver) generateAnonymousTraces() ptrace.Traces {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	ss := rs.ScopeSpans().AppendEmpty()
+
+	for i := 0; i < r.cfg.NumberOfTraces; i++ {
+		span := ss.Spans().AppendEmpty()
+		span.SetName("synthetic-span")
+		span.SetSpanID(randSpanID())
+		span.SetTraceID(randTraceID())
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	}
+
+	return traces
+}
+
+func populateResource(resource pcommon.Resource, profile ResourceProfile) {
+	attrs := resource.Attributes()
+	attrs.PutStr(semconvServiceName, profile.ServiceName)
+	if profile.ServiceVersion != "" {
+		attrs.PutStr(semconvServiceVersion, profile.ServiceVersion)
+	}
+	if profile.CloudProvider != "" {
+		attrs.PutStr(semconvCloudProvider, profile.CloudProvider)
+	}
+	if profile.CloudRegion != "" {
+		attrs.PutStr(semconvCloudRegion, profile.CloudRegion)
+	}
+}
+
+func populateScope(scope pcommon.InstrumentationScope, profile ScopeProfile) {
+	scope.SetName(profile.Name)
+	scope.SetVersion(profile.Version)
+	for k, v := range profile.Attributes {
+		scope.Attributes().PutStr(k, v)
+	}
+}
+
+func randTraceID() pcommon.TraceID {
+	var tid [16]byte
+	rand.Read(tid[:])
+	return pcommon.TraceID(tid)
+}
+
+func randSpanID() pcommon.SpanID {
+	var sid [8]byte
+	rand.Read(sid[:])
+	return pcommon.SpanID(sid)
+}
@@ -0,0 +1,75 @@
+package tailtracer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerateTracesWithResources configures two resources with two scopes
+// each and checks that generateTraces emits one ResourceSpans per resource
+// (tagged with the right service.name), one ScopeSpans per scope (tagged
+// with the right scope name), and that span parent IDs chain linearly across
+// every resource/scope in encounter order, modeling the Atm->BackendSystem
+// call graph a single trace walks.
+func TestGenerateTracesWithResources(t *testing.T) {
+	cfg := &Config{
+		NumberOfTraces: 1,
+		Resources: []ResourceProfile{
+			{
+				ServiceName: "atm-service",
+				Scopes: []ScopeProfile{
+					{Name: "atmscope-card"},
+					{Name: "atmscope-withdraw"},
+				},
+			},
+			{
+				ServiceName: "backend-service",
+				Scopes: []ScopeProfile{
+					{Name: "backendscope-authorize"},
+					{Name: "backendscope-ledger"},
+				},
+			},
+		},
+	}
+	r := &tailTracerReceiver{cfg: cfg}
+
+	traces := r.generateTraces()
+
+	require.Equal(t, 2, traces.ResourceSpans().Len())
+
+	atmRS := traces.ResourceSpans().At(0)
+	serviceName, ok := atmRS.Resource().Attributes().Get(semconvServiceName)
+	require.True(t, ok)
+	assert.Equal(t, "atm-service", serviceName.Str())
+	require.Equal(t, 2, atmRS.ScopeSpans().Len())
+	assert.Equal(t, "atmscope-card", atmRS.ScopeSpans().At(0).Scope().Name())
+	assert.Equal(t, "atmscope-withdraw", atmRS.ScopeSpans().At(1).Scope().Name())
+
+	backendRS := traces.ResourceSpans().At(1)
+	serviceName, ok = backendRS.Resource().Attributes().Get(semconvServiceName)
+	require.True(t, ok)
+	assert.Equal(t, "backend-service", serviceName.Str())
+	require.Equal(t, 2, backendRS.ScopeSpans().Len())
+	assert.Equal(t, "backendscope-authorize", backendRS.ScopeSpans().At(0).Scope().Name())
+	assert.Equal(t, "backendscope-ledger", backendRS.ScopeSpans().At(1).Scope().Name())
+
+	// Every scope contributes exactly one span per trace, and each span's
+	// parent is the span immediately before it in resource/scope order.
+	cardSpan := atmRS.ScopeSpans().At(0).Spans().At(0)
+	withdrawSpan := atmRS.ScopeSpans().At(1).Spans().At(0)
+	authorizeSpan := backendRS.ScopeSpans().At(0).Spans().At(0)
+	ledgerSpan := backendRS.ScopeSpans().At(1).Spans().At(0)
+
+	assert.True(t, cardSpan.ParentSpanID().IsEmpty())
+	assert.Equal(t, cardSpan.SpanID(), withdrawSpan.ParentSpanID())
+	assert.Equal(t, withdrawSpan.SpanID(), authorizeSpan.ParentSpanID())
+	assert.Equal(t, authorizeSpan.SpanID(), ledgerSpan.ParentSpanID())
+
+	// All four spans belong to the same trace.
+	traceID := cardSpan.TraceID()
+	assert.Equal(t, traceID, withdrawSpan.TraceID())
+	assert.Equal(t, traceID, authorizeSpan.TraceID())
+	assert.Equal(t, traceID, ledgerSpan.TraceID())
+}
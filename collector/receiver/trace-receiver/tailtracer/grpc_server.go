@@ -0,0 +1,77 @@
+package tailtracer
+
+import (
+	"context"
+
+	arrowpb "github.com/open-telemetry/otel-arrow/api/experimental/arrow/v1"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// grpcTracesServer implements ptraceotlp.GRPCServer, accepting
+// ExportTraceServiceRequest calls and forwarding them to consumer.Traces. When
+// Arrow is configured it also hosts the ArrowTracesService stream on the same
+// gRPC server, since both transports share one endpoint.
+type grpcTracesServer struct {
+	ptraceotlp.UnimplementedGRPCServer
+
+	settings *configgrpc.GRPCServerSettings
+	arrow    *ArrowConfig
+	logger   *zap.Logger
+	consumer consumer.Traces
+
+	server *grpc.Server
+}
+
+func newGRPCTracesServer(settings *configgrpc.GRPCServerSettings, arrow *ArrowConfig, logger *zap.Logger, next consumer.Traces) *grpcTracesServer {
+	return &grpcTracesServer{settings: settings, arrow: arrow, logger: logger, consumer: next}
+}
+
+func (s *grpcTracesServer) Export(ctx context.Context, req ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	return exportTraces(ctx, s.consumer, req)
+}
+
+func (s *grpcTracesServer) Start(_ context.Context, host component.Host) error {
+	var opts []grpc.ServerOption
+	if s.arrow != nil && s.arrow.MaxStreamLifetime > 0 {
+		opts = append(opts, grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionAge: s.arrow.MaxStreamLifetime,
+		}))
+	}
+
+	server, err := s.settings.ToServer(host, opts...)
+	if err != nil {
+		return err
+	}
+	s.server = server
+	ptraceotlp.RegisterGRPCServer(s.server, s)
+
+	if s.arrow != nil {
+		arrowpb.RegisterArrowTracesServiceServer(s.server, newArrowStreamServer(s.arrow, s.logger, s.consumer))
+	}
+
+	listener, err := s.settings.NetAddr.Listen()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil {
+			s.logger.Error("tailtracer grpc server stopped", zap.Error(err))
+		}
+	}()
+
+	return nil
+}
+
+func (s *grpcTracesServer) Shutdown(context.Context) error {
+	if s.server != nil {
+		s.server.GracefulStop()
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package tailtracer
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.uber.org/zap"
+)
+
+// httpTracesServer accepts OTLP/HTTP ExportTraceServiceRequest payloads on
+// the configured endpoint and forwards them to consumer.Traces.
+type httpTracesServer struct {
+	settings *confighttp.HTTPServerSettings
+	logger   *zap.Logger
+	consumer consumer.Traces
+
+	server *http.Server
+}
+
+func newHTTPTracesServer(settings *confighttp.HTTPServerSettings, logger *zap.Logger, next consumer.Traces) *httpTracesServer {
+	return &httpTracesServer{settings: settings, logger: logger, consumer: next}
+}
+
+func (s *httpTracesServer) Start(_ context.Context, host component.Host) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/traces", s.handleTraces)
+
+	server, err := s.settings.ToServer(host, nil, mux)
+	if err != nil {
+		return err
+	}
+	s.server = server
+
+	listener, err := s.settings.ToListener()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			s.logger.Error("tailtracer http server stopped", zap.Error(err))
+		}
+	}()
+
+	return nil
+}
+
+func (s *httpTracesServer) Shutdown(ctx context.Context) error {
+	if s.server != nil {
+		return s.server.Shutdown(ctx)
+	}
+	return nil
+}
+
+func (s *httpTracesServer) handleTraces(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req := ptraceotlp.NewExportRequest()
+	if err := req.UnmarshalProto(body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := exportTraces(r.Context(), s.consumer, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respBytes, err := resp.MarshalProto()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(respBytes)
+}
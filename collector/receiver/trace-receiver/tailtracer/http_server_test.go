@@ -0,0 +1,70 @@
+package tailtracer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.uber.org/zap"
+)
+
+// freeLocalAddr reserves an ephemeral TCP port and returns its address,
+// releasing the listener immediately so the server under test can bind it.
+func freeLocalAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+	return addr
+}
+
+// TestHTTPTracesServerExport starts a real OTLP/HTTP listener, posts a
+// marshaled ExportTraceServiceRequest to it, and checks the pipeline
+// consumer received the traces and the response body round-trips through
+// ptraceotlp, exercising handleTraces end-to-end rather than in isolation.
+func TestHTTPTracesServerExport(t *testing.T) {
+	settings := &confighttp.HTTPServerSettings{Endpoint: freeLocalAddr(t)}
+
+	sink := new(consumertest.TracesSink)
+	server := newHTTPTracesServer(settings, zap.NewNop(), sink)
+	require.NoError(t, server.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, server.Shutdown(context.Background())) }()
+
+	traces := ptrace.NewTraces()
+	span := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("atm-withdraw")
+
+	body, err := ptraceotlp.NewExportRequestFromTraces(traces).MarshalProto()
+	require.NoError(t, err)
+
+	var resp *http.Response
+	require.Eventually(t, func() bool {
+		resp, err = http.Post("http://"+settings.Endpoint+"/v1/traces", "application/x-protobuf", bytes.NewReader(body))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond, "http server never came up")
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	exportResp := ptraceotlp.NewExportResponse()
+	require.NoError(t, exportResp.UnmarshalProto(respBody))
+
+	require.Len(t, sink.AllTraces(), 1)
+	got := sink.AllTraces()[0]
+	require.Equal(t, 1, got.SpanCount())
+	assert.Equal(t, "atm-withdraw", got.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+}
@@ -0,0 +1,72 @@
+package tailtracer
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// otlpReceiverServer hosts the OTLP-over-gRPC and OTLP/HTTP listeners that
+// feed ExportTraceServiceRequest payloads into the receiver's consumer,
+// bypassing the synthetic generator entirely.
+type otlpReceiverServer struct {
+	protocols *Protocols
+	arrow     *ArrowConfig
+	logger    *zap.Logger
+	consumer  consumer.Traces
+
+	grpc *grpcTracesServer
+	http *httpTracesServer
+}
+
+func newOTLPReceiverServer(protocols *Protocols, arrow *ArrowConfig, logger *zap.Logger, next consumer.Traces) *otlpReceiverServer {
+	s := &otlpReceiverServer{protocols: protocols, arrow: arrow, logger: logger, consumer: next}
+
+	if protocols.GRPC != nil {
+		s.grpc = newGRPCTracesServer(protocols.GRPC, arrow, logger, next)
+	}
+	if protocols.HTTP != nil {
+		s.http = newHTTPTracesServer(protocols.HTTP, logger, next)
+	}
+
+	return s
+}
+
+func (s *otlpReceiverServer) Start(ctx context.Context, host component.Host) error {
+	if s.grpc != nil {
+		if err := s.grpc.Start(ctx, host); err != nil {
+			return err
+		}
+	}
+	if s.http != nil {
+		if err := s.http.Start(ctx, host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *otlpReceiverServer) Shutdown(ctx context.Context) error {
+	var err error
+	if s.grpc != nil {
+		err = multierr.Append(err, s.grpc.Shutdown(ctx))
+	}
+	if s.http != nil {
+		err = multierr.Append(err, s.http.Shutdown(ctx))
+	}
+	return err
+}
+
+// exportTraces hands a decoded ExportTraceServiceRequest to the pipeline
+// consumer; shared by both the gRPC and HTTP listeners.
+func exportTraces(ctx context.Context, next consumer.Traces, req ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	resp := ptraceotlp.NewExportResponse()
+	if err := next.ConsumeTraces(ctx, req.Traces()); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,87 @@
+package tailtracer
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.uber.org/zap"
+)
+
+// tailTracerReceiver either synthesizes traces on a timer or ingests them
+// from an OTLP server, depending on how Config.Protocols is set.
+type tailTracerReceiver struct {
+	cfg      *Config
+	logger   *zap.Logger
+	consumer consumer.Traces
+
+	cancel context.CancelFunc
+
+	otlpServer *otlpReceiverServer
+}
+
+func newTailTracerReceiver(cfg *Config, set component.ReceiverCreateSettings, next consumer.Traces) (*tailTracerReceiver, error) {
+	r := &tailTracerReceiver{
+		cfg:      cfg,
+		logger:   set.Logger,
+		consumer: next,
+	}
+
+	if cfg.Protocols != nil {
+		r.otlpServer = newOTLPReceiverServer(cfg.Protocols, cfg.Arrow, set.Logger, next)
+	}
+
+	return r, nil
+}
+
+// Start implements component.Component. When a protocols block is configured
+// it starts the OTLP gRPC/HTTP servers; otherwise it begins the synthetic
+// trace generation loop.
+func (r *tailTracerReceiver) Start(ctx context.Context, host component.Host) error {
+	if r.otlpServer != nil {
+		if err := r.otlpServer.Start(ctx, host); err != nil {
+			return err
+		}
+	}
+
+	// Synthetic generation is strictly the fallback for when no real traces
+	// are coming in; a configured protocol or arrow stream always wins, even
+	// if NumberOfTraces is left at its nonzero default.
+	if r.cfg.Protocols == nil && r.cfg.Arrow == nil && r.cfg.NumberOfTraces > 0 {
+		runCtx, cancel := context.WithCancel(context.Background())
+		r.cancel = cancel
+		go r.startPullingTraces(runCtx, r.cfg.Interval)
+	}
+
+	return nil
+}
+
+// Shutdown implements component.Component.
+func (r *tailTracerReceiver) Shutdown(ctx context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+
+	if r.otlpServer != nil {
+		return r.otlpServer.Shutdown(ctx)
+	}
+
+	return nil
+}
+
+func (r *tailTracerReceiver) startPullingTraces(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.consumer.ConsumeTraces(ctx, r.generateTraces()); err != nil {
+				r.logger.Error("failed to consume synthetic traces", zap.Error(err))
+			}
+		}
+	}
+}